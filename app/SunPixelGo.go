@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/binary"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"image"
@@ -13,8 +13,6 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-
-	"github.com/MachineMC/NBT"
 )
 
 type Color struct {
@@ -26,27 +24,144 @@ type BlockMapping struct {
 	BlockData int    `json:"block_data"`
 }
 
+// DitherMode selects how averaged pixels are pushed toward the block
+// palette before quantization.
+type DitherMode int
+
+const (
+	DitherNone DitherMode = iota
+	DitherFloydSteinberg
+	DitherOrderedBayer
+)
+
+// ColorSpace selects the metric ColorDistance uses to compare colors.
+type ColorSpace int
+
+const (
+	ColorSpaceRGB ColorSpace = iota
+	ColorSpaceCIELAB
+)
+
+// bayerMatrix8 is the standard 8x8 ordered-dithering threshold matrix,
+// values in [0, 63].
+var bayerMatrix8 = [8][8]int{
+	{0, 48, 12, 60, 3, 51, 15, 63},
+	{32, 16, 44, 28, 35, 19, 47, 31},
+	{8, 56, 4, 52, 11, 59, 7, 55},
+	{40, 24, 36, 20, 43, 27, 39, 23},
+	{2, 50, 14, 62, 1, 49, 13, 61},
+	{34, 18, 46, 30, 33, 17, 45, 29},
+	{10, 58, 6, 54, 9, 57, 5, 53},
+	{42, 26, 38, 22, 41, 25, 37, 21},
+}
+
 type ImageToSchem struct {
-	colorToBlock    map[Color]BlockMapping
-	blockPalette    []string
-	blockData       [][][]int
-	width           int
-	height          int
-	depth           int
-	originalWidth   int
-	originalHeight  int
-	pixels          [][]Color
+	colorToBlock   map[Color]BlockMapping
+	blockPalette   []string
+	blockData      [][][]int
+	width          int
+	height         int
+	depth          int
+	originalWidth  int
+	originalHeight int
+	pixels         [][]Color
+	ditherMode     DitherMode
+	colorSpace     ColorSpace
+	orderedSpread  float64
+	closestCache   map[Color]closestMatch
+	paletteTree    *kdNode
+	mapArtMode     bool
+	minY           int
+	maxY           int
+	writer         SchematicWriter
+	resampleFilter ResampleFilter
 }
 
+var errNoImageLoaded = fmt.Errorf("no image loaded")
+
 func NewImageToSchem() *ImageToSchem {
 	return &ImageToSchem{
-		colorToBlock: make(map[Color]BlockMapping),
-		depth:        1,
+		colorToBlock:   make(map[Color]BlockMapping),
+		depth:          1,
+		ditherMode:     DitherNone,
+		colorSpace:     ColorSpaceRGB,
+		orderedSpread:  32,
+		writer:         SpongeV2Writer{},
+		resampleFilter: FilterBox,
+	}
+}
+
+// SetResampleFilter selects the filter resampleGrid uses to downscale the
+// source image into the target grid.
+func (its *ImageToSchem) SetResampleFilter(filter ResampleFilter) {
+	its.resampleFilter = filter
+}
+
+// SetFormat selects the schematic format SaveSchem writes: "sponge2"
+// (default), "sponge3", or "mcedit".
+func (its *ImageToSchem) SetFormat(format string) error {
+	switch format {
+	case "", "sponge2":
+		its.writer = SpongeV2Writer{}
+	case "sponge3":
+		its.writer = SpongeV3Writer{}
+	case "mcedit":
+		its.writer = MCEditWriter{}
+	default:
+		return fmt.Errorf("unknown schematic format %q", format)
+	}
+	return nil
+}
+
+// SetDitherMode selects how resampled pixels are quantized into the block
+// palette. DitherFloydSteinberg diffuses quantization error to
+// neighboring pixels; DitherOrderedBayer perturbs each pixel with a fixed
+// 8x8 threshold matrix before lookup.
+func (its *ImageToSchem) SetDitherMode(mode DitherMode) {
+	its.ditherMode = mode
+}
+
+// SetColorSpace selects the metric used by ColorDistance. ColorSpaceCIELAB
+// computes CIE76 Delta-E instead of weighted-RGB Euclidean distance.
+func (its *ImageToSchem) SetColorSpace(space ColorSpace) {
+	its.colorSpace = space
+}
+
+// SetMapArtMode switches GenerateSchem into multi-layer "staircase"
+// mapmaker mode, where each column's block is raised or lowered by one
+// block relative to its northern neighbor to render as a dark, normal or
+// light shade on an in-game map. The resulting build's vertical extent is
+// clamped to [minY, maxY].
+func (its *ImageToSchem) SetMapArtMode(enabled bool, minY, maxY int) {
+	its.mapArtMode = enabled
+	its.minY = minY
+	its.maxY = maxY
+}
+
+// ensureBlockPalette builds its.blockPalette from the loaded block
+// mappings the first time it's needed, then leaves it untouched so
+// repeated GenerateSchem calls (e.g. one per animation frame) keep
+// assigning the same blocks to the same palette indices.
+func (its *ImageToSchem) ensureBlockPalette() {
+	if len(its.blockPalette) > 0 {
+		return
+	}
+
+	paletteSet := make(map[string]bool)
+	for _, mapping := range its.colorToBlock {
+		paletteSet[mapping.BlockName] = true
+	}
+
+	its.blockPalette = make([]string, 0, len(paletteSet))
+	for blockName := range paletteSet {
+		its.blockPalette = append(its.blockPalette, blockName)
 	}
 }
 
 func (its *ImageToSchem) LoadBlockMappings(selectedBlocks []string) error {
 	its.colorToBlock = make(map[Color]BlockMapping)
+	its.paletteTree = nil
+	its.blockPalette = nil
 	blockDir := "block"
 
 	if _, err := os.Stat(blockDir); os.IsNotExist(err) {
@@ -60,7 +175,7 @@ func (its *ImageToSchem) LoadBlockMappings(selectedBlocks []string) error {
 
 	for _, file := range files {
 		blockName := strings.TrimSuffix(filepath.Base(file), ".json")
-		
+
 		// Check if this block is selected
 		selected := false
 		for _, selectedBlock := range selectedBlocks {
@@ -69,7 +184,7 @@ func (its *ImageToSchem) LoadBlockMappings(selectedBlocks []string) error {
 				break
 			}
 		}
-		
+
 		if !selected {
 			continue
 		}
@@ -92,7 +207,7 @@ func (its *ImageToSchem) LoadBlockMappings(selectedBlocks []string) error {
 				if ok && len(blockArr) >= 2 {
 					blockName := blockArr[0].(string)
 					blockData := int(blockArr[1].(float64))
-					
+
 					// Parse color
 					colorStr = strings.Trim(colorStr, "()")
 					parts := strings.Split(colorStr, ",")
@@ -100,7 +215,7 @@ func (its *ImageToSchem) LoadBlockMappings(selectedBlocks []string) error {
 						r, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
 						g, _ := strconv.Atoi(strings.TrimSpace(parts[1]))
 						b, _ := strconv.Atoi(strings.TrimSpace(parts[2]))
-						
+
 						its.colorToBlock[Color{uint8(r), uint8(g), uint8(b)}] = BlockMapping{
 							BlockName: blockName,
 							BlockData: blockData,
@@ -112,7 +227,7 @@ func (its *ImageToSchem) LoadBlockMappings(selectedBlocks []string) error {
 
 			blockName, ok1 := blockMap["block_name"].(string)
 			blockData, ok2 := blockMap["block_data"].(float64)
-			
+
 			if ok1 && ok2 {
 				// Parse color
 				colorStr = strings.Trim(colorStr, "()")
@@ -121,7 +236,7 @@ func (its *ImageToSchem) LoadBlockMappings(selectedBlocks []string) error {
 					r, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
 					g, _ := strconv.Atoi(strings.TrimSpace(parts[1]))
 					b, _ := strconv.Atoi(strings.TrimSpace(parts[2]))
-					
+
 					its.colorToBlock[Color{uint8(r), uint8(g), uint8(b)}] = BlockMapping{
 						BlockName: blockName,
 						BlockData: int(blockData),
@@ -139,6 +254,15 @@ func (its *ImageToSchem) LoadBlockMappings(selectedBlocks []string) error {
 }
 
 func (its *ImageToSchem) ColorDistance(c1, c2 Color) float64 {
+	if its.colorSpace == ColorSpaceCIELAB {
+		l1, a1, b1 := rgbToLab(c1)
+		l2, a2, b2 := rgbToLab(c2)
+		dl := l1 - l2
+		da := a1 - a2
+		db := b1 - b2
+		return math.Sqrt(dl*dl + da*da + db*db)
+	}
+
 	r1, g1, b1 := float64(c1.R), float64(c1.G), float64(c1.B)
 	r2, g2, b2 := float64(c2.R), float64(c2.G), float64(c2.B)
 	rMean := (r1 + r2) / 2
@@ -153,34 +277,121 @@ func (its *ImageToSchem) ColorDistance(c1, c2 Color) float64 {
 			(2+(255-rMean)/256)*(bDiff*bDiff))
 }
 
-func (its *ImageToSchem) FindClosestColor(target Color) (BlockMapping, bool) {
-	var closestColor Color
-	minDistance := math.MaxFloat64
-	found := false
+// rgbToLab converts an 8-bit sRGB color to CIE L*a*b* via the CIE XYZ
+// (D65) intermediate space, for use with the CIELAB color space's
+// Delta-E*76 distance.
+func rgbToLab(c Color) (l, a, b float64) {
+	toLinear := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f > 0.04045 {
+			return math.Pow((f+0.055)/1.055, 2.4)
+		}
+		return f / 12.92
+	}
+
+	r, g, bl := toLinear(c.R), toLinear(c.G), toLinear(c.B)
+
+	x := r*0.4124564 + g*0.3575761 + bl*0.1804375
+	y := r*0.2126729 + g*0.7151522 + bl*0.0721750
+	z := r*0.0193339 + g*0.1191920 + bl*0.9503041
 
-	for colorKey := range its.colorToBlock {
-		distance := its.ColorDistance(target, colorKey)
-		if distance < minDistance {
-			minDistance = distance
-			closestColor = colorKey
-			found = true
+	// Normalize against the D65 reference white.
+	x /= 0.95047
+	z /= 1.08883
+
+	f := func(t float64) float64 {
+		if t > 0.008856 {
+			return math.Cbrt(t)
 		}
+		return 7.787*t + 16.0/116.0
+	}
+
+	fx, fy, fz := f(x), f(y), f(z)
+
+	l = 116*fy - 16
+	a = 500 * (fx - fy)
+	b = 200 * (fy - fz)
+	return l, a, b
+}
+
+// FindClosestColor resolves target to the nearest palette entry, along
+// with the palette color that matched it. The k-d tree is pruned on the
+// weighted-RGB redmean metric (see kdDistance), so it's only valid for
+// ColorSpaceRGB; ColorSpaceCIELAB falls back to a brute-force scan under
+// the real ColorDistance metric instead.
+func (its *ImageToSchem) FindClosestColor(target Color) (Color, BlockMapping, bool) {
+	var matched Color
+	var mapping BlockMapping
+	var found bool
+
+	if its.colorSpace == ColorSpaceCIELAB {
+		matched, mapping, found = its.bruteForceClosestColor(target)
+	} else {
+		its.ensurePaletteTree()
+		matched, mapping, found = its.paletteTree.nearest(target)
 	}
 
 	if found {
-		return its.colorToBlock[closestColor], true
+		return matched, mapping, true
 	}
-	return BlockMapping{"minecraft:white_concrete", 0}, false
+	return target, BlockMapping{"minecraft:white_concrete", 0}, false
+}
+
+// closestMatch is a FindClosestColor result cached by blockIndexFor: the
+// palette index plus the source color that index was matched from, so
+// Floyd-Steinberg error diffusion can read back the actual quantized
+// color without re-scanning the palette by block name.
+type closestMatch struct {
+	index int
+	color Color
+}
+
+// blockIndexFor resolves target to a palette index and the source color
+// it matched, memoizing FindClosestColor lookups in its.closestCache so
+// repeated (or error-diffused) colors across a large image aren't
+// re-scanned against the whole palette each time.
+func (its *ImageToSchem) blockIndexFor(target Color) (int, Color) {
+	if its.closestCache == nil {
+		its.closestCache = make(map[Color]closestMatch)
+	}
+	if match, ok := its.closestCache[target]; ok {
+		return match.index, match.color
+	}
+
+	matchedColor, blockMapping, found := its.FindClosestColor(target)
+	index := 0
+	if found {
+		for i, blockName := range its.blockPalette {
+			if blockName == blockMapping.BlockName {
+				index = i
+				break
+			}
+		}
+	} else {
+		matchedColor = target
+	}
+
+	its.closestCache[target] = closestMatch{index, matchedColor}
+	return index, matchedColor
+}
+
+func clampChannel(v float64) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
 }
 
 func (its *ImageToSchem) LoadImage(imagePath string) error {
-	file, err := os.Open(imagePath)
+	data, err := os.ReadFile(imagePath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	img, _, err := image.Decode(file)
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
@@ -199,6 +410,17 @@ func (its *ImageToSchem) LoadImage(imagePath string) error {
 		}
 	}
 
+	// JPEG photos from phones commonly carry an EXIF orientation tag
+	// rather than storing pixels upright; rotate/flip them back.
+	orientation := readExifOrientation(data)
+	if orientation != 1 {
+		its.pixels = applyExifOrientation(its.pixels, orientation)
+		its.originalHeight = len(its.pixels)
+		if its.originalHeight > 0 {
+			its.originalWidth = len(its.pixels[0])
+		}
+	}
+
 	return nil
 }
 
@@ -207,33 +429,30 @@ func (its *ImageToSchem) SetSize(width, height int) {
 	its.height = max(1, height)
 }
 
-func (its *ImageToSchem) GenerateSchem() error {
-	// Initialize block palette
-	paletteSet := make(map[string]bool)
-	for _, mapping := range its.colorToBlock {
-		paletteSet[mapping.BlockName] = true
-	}
-	
-	its.blockPalette = make([]string, 0, len(paletteSet))
-	for blockName := range paletteSet {
-		its.blockPalette = append(its.blockPalette, blockName)
-	}
-
-	// Initialize block data
-	its.blockData = make([][][]int, its.depth)
-	for z := 0; z < its.depth; z++ {
-		its.blockData[z] = make([][]int, its.height)
-		for y := 0; y < its.height; y++ {
-			its.blockData[z][y] = make([]int, its.width)
-		}
+// resampleGrid downscales the loaded source image into a
+// its.width x its.height grid of colors, using its.resampleFilter.
+func (its *ImageToSchem) resampleGrid() [][]Color {
+	switch its.resampleFilter {
+	case FilterBilinear:
+		return its.resampleSeparable(bilinearKernel, 1)
+	case FilterLanczos3:
+		return its.resampleSeparable(lanczos3Kernel, 3)
+	case FilterCatmullRom:
+		return its.resampleSeparable(catmullRomKernel, 2)
+	default:
+		return its.resampleBox()
 	}
+}
 
-	// Calculate scale
+// resampleBox box-averages the loaded source image down into a
+// its.width x its.height grid of colors.
+func (its *ImageToSchem) resampleBox() [][]Color {
 	scaleX := float64(its.originalWidth) / float64(its.width)
 	scaleY := float64(its.originalHeight) / float64(its.height)
 
-	// Fill block data
+	grid := make([][]Color, its.height)
 	for y := 0; y < its.height; y++ {
+		grid[y] = make([]Color, its.width)
 		for x := 0; x < its.width; x++ {
 			srcX := int(float64(x) * scaleX)
 			srcY := int(float64(y) * scaleY)
@@ -262,76 +481,122 @@ func (its *ImageToSchem) GenerateSchem() error {
 				avgB /= float64(count)
 			}
 
-			avgColor := Color{uint8(avgR), uint8(avgG), uint8(avgB)}
-			blockMapping, found := its.FindClosestColor(avgColor)
-			
-			var blockIndex int
-			if found {
-				// Find block index in palette
-				for i, blockName := range its.blockPalette {
-					if blockName == blockMapping.BlockName {
-						blockIndex = i
-						break
-					}
-				}
-			}
-
-			its.blockData[0][y][x] = blockIndex
+			grid[y][x] = Color{uint8(avgR), uint8(avgG), uint8(avgB)}
 		}
 	}
 
-	return nil
+	return grid
 }
 
-func (its *ImageToSchem) SaveSchem(outputPath string) error {
-	if !strings.HasSuffix(strings.ToLower(outputPath), ".schem") {
-		outputPath += ".schem"
+func (its *ImageToSchem) GenerateSchem() error {
+	if its.mapArtMode {
+		return its.generateMapArt()
 	}
 
-	// Create palette map for NBT
-	paletteMap := make(map[string]int32)
-	for i, blockName := range its.blockPalette {
-		paletteMap[blockName] = int32(i)
-	}
+	its.ensureBlockPalette()
 
-	// Flatten block data
-	blockData := make([]byte, its.width*its.height*its.depth)
-	index := 0
+	// Initialize block data
+	its.blockData = make([][][]int, its.depth)
 	for z := 0; z < its.depth; z++ {
+		its.blockData[z] = make([][]int, its.height)
+		for y := 0; y < its.height; y++ {
+			its.blockData[z][y] = make([]int, its.width)
+		}
+	}
+
+	its.closestCache = make(map[Color]closestMatch)
+
+	grid := its.resampleGrid()
+	its.quantizeGrid(grid)
+
+	return nil
+}
+
+// quantizeGrid maps each resampled pixel in grid to a palette index
+// according to its.ditherMode, writing the result into its.blockData[0].
+func (its *ImageToSchem) quantizeGrid(grid [][]Color) {
+	switch its.ditherMode {
+	case DitherFloydSteinberg:
+		its.quantizeFloydSteinberg(grid)
+	case DitherOrderedBayer:
+		its.quantizeOrderedBayer(grid)
+	default:
 		for y := 0; y < its.height; y++ {
 			for x := 0; x < its.width; x++ {
-				blockData[index] = byte(its.blockData[z][y][x])
-				index++
+				index, _ := its.blockIndexFor(grid[y][x])
+				its.blockData[0][y][x] = index
 			}
 		}
 	}
+}
 
-	// Create NBT structure
-	schematic := map[string]interface{}{
-		"Version":      int32(2),
-		"DataVersion":  int32(3100),
-		"Width":        int16(its.width),
-		"Height":       int16(its.depth),
-		"Length":       int16(its.height),
-		"Offset":       []int32{0, 0, 0},
-		"Palette":      paletteMap,
-		"BlockData":    blockData,
-		"BlockEntities": []interface{}{},
+// quantizeFloydSteinberg quantizes grid in place, diffusing each pixel's
+// quantization error to its unvisited neighbors with the classic
+// 7/16, 3/16, 5/16, 1/16 weights. Every pixel's "quantized" color comes
+// from blockIndexFor, so in ColorSpaceRGB mode the error diffused here is
+// only as accurate as kdNode.nearest's bound (see kdAxisMinCoeff) -
+// a wrong nearest-color pick wouldn't just misplace one pixel, it'd feed
+// a wrong error term into every neighbor this diffuses to.
+func (its *ImageToSchem) quantizeFloydSteinberg(grid [][]Color) {
+	for y := 0; y < its.height; y++ {
+		for x := 0; x < its.width; x++ {
+			old := grid[y][x]
+			index, quantized := its.blockIndexFor(old)
+			its.blockData[0][y][x] = index
+
+			errR := float64(old.R) - float64(quantized.R)
+			errG := float64(old.G) - float64(quantized.G)
+			errB := float64(old.B) - float64(quantized.B)
+
+			its.diffuseError(grid, x+1, y, errR, errG, errB, 7.0/16)
+			its.diffuseError(grid, x-1, y+1, errR, errG, errB, 3.0/16)
+			its.diffuseError(grid, x, y+1, errR, errG, errB, 5.0/16)
+			its.diffuseError(grid, x+1, y+1, errR, errG, errB, 1.0/16)
+		}
 	}
+}
 
-	// Write NBT file
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return err
+func (its *ImageToSchem) diffuseError(grid [][]Color, x, y int, errR, errG, errB, weight float64) {
+	if x < 0 || x >= its.width || y < 0 || y >= its.height {
+		return
 	}
-	defer file.Close()
+	c := grid[y][x]
+	grid[y][x] = Color{
+		clampChannel(float64(c.R) + errR*weight),
+		clampChannel(float64(c.G) + errG*weight),
+		clampChannel(float64(c.B) + errB*weight),
+	}
+}
 
-	encoder := nbt.NewEncoderWithEncoding(file, nbt.BigEndian)
-	if err := encoder.Encode(schematic); err != nil {
-		return err
+// quantizeOrderedBayer perturbs each pixel by the 8x8 Bayer threshold
+// matrix before quantization, scattering banding into a dot pattern
+// instead of diffusing it like Floyd-Steinberg.
+func (its *ImageToSchem) quantizeOrderedBayer(grid [][]Color) {
+	for y := 0; y < its.height; y++ {
+		for x := 0; x < its.width; x++ {
+			threshold := float64(bayerMatrix8[y%8][x%8])/64 - 0.5
+			offset := threshold * its.orderedSpread
+
+			c := grid[y][x]
+			perturbed := Color{
+				clampChannel(float64(c.R) + offset),
+				clampChannel(float64(c.G) + offset),
+				clampChannel(float64(c.B) + offset),
+			}
+
+			index, _ := its.blockIndexFor(perturbed)
+			its.blockData[0][y][x] = index
+		}
 	}
+}
 
-	return nil
+// SaveSchem writes the generated build to outputPath using its.writer
+// (Sponge v2 by default - see SetFormat).
+func (its *ImageToSchem) SaveSchem(outputPath string) error {
+	if its.writer == nil {
+		its.writer = SpongeV2Writer{}
+	}
+	return its.writer.Write(its, outputPath)
 }
 
 func (its *ImageToSchem) Convert(inputImage, outputSchem string, width, height int, selectedBlocks []string) error {
@@ -370,26 +635,48 @@ func min(a, b int) int {
 	return b
 }
 
+// parseFormatFlag pulls a "-format=sponge2|sponge3|mcedit" flag out of
+// args, returning the remaining positional arguments and the selected
+// format ("sponge2" if the flag is absent).
+func parseFormatFlag(args []string) (positional []string, format string) {
+	format = "sponge2"
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "-format="); ok {
+			format = value
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	return positional, format
+}
+
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: SunPixel <input_image> <output_schem> [width] [height]")
-		fmt.Println("Example: SunPixel image.png output.schem 64 64")
+	args, format := parseFormatFlag(os.Args[1:])
+
+	if len(args) < 2 {
+		fmt.Println("Usage: SunPixel <input_image> <output_schem> [width] [height] [-format=sponge2|sponge3|mcedit]")
+		fmt.Println("Example: SunPixel image.png output.schem 64 64 -format=mcedit")
 		return
 	}
 
-	inputImage := os.Args[1]
-	outputSchem := os.Args[2]
-	
+	inputImage := args[0]
+	outputSchem := args[1]
+
 	var width, height int
-	if len(os.Args) >= 5 {
-		width, _ = strconv.Atoi(os.Args[3])
-		height, _ = strconv.Atoi(os.Args[4])
+	if len(args) >= 4 {
+		width, _ = strconv.Atoi(args[2])
+		height, _ = strconv.Atoi(args[3])
 	}
 
 	// Default block selections
 	selectedBlocks := []string{"wool", "concrete"}
 
 	converter := NewImageToSchem()
+	if err := converter.SetFormat(format); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if err := converter.Convert(inputImage, outputSchem, width, height, selectedBlocks); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
@@ -397,4 +684,4 @@ func main() {
 
 	fmt.Printf("Successfully converted %s to %s\n", inputImage, outputSchem)
 	fmt.Printf("Dimensions: %d x %d blocks\n", converter.width, converter.height)
-}
\ No newline at end of file
+}
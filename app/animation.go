@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// ConvertAnimation decodes a multi-frame GIF or APNG and either writes one
+// .schem per frame (consistent blocks across frames, for slide-show
+// builders) or a single 3-D "flipbook" schematic with one Z-layer per
+// frame (for redstone/piston display contraptions), depending on
+// flipbook. maxColors, if > 0, runs median-cut once over every frame's
+// combined pixels so blocks don't shimmer between frames; 0 skips
+// quantization.
+func (its *ImageToSchem) ConvertAnimation(inputPath, outputDir string, width, height, maxColors int, selectedBlocks []string, flipbook bool) error {
+	if err := its.LoadBlockMappings(selectedBlocks); err != nil {
+		return err
+	}
+
+	images, err := decodeAnimationFrames(inputPath)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return fmt.Errorf("no frames decoded from %s", inputPath)
+	}
+
+	frameGrids := make([][][]Color, len(images))
+	for i, img := range images {
+		frameGrids[i] = frameToColorGrid(img)
+	}
+
+	if maxColors > 0 {
+		quantizeFramesUnion(frameGrids, maxColors)
+	}
+
+	if width == 0 || height == 0 {
+		height = len(frameGrids[0])
+		if height > 0 {
+			width = len(frameGrids[0][0])
+		}
+	}
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return err
+	}
+
+	its.ensureBlockPalette()
+
+	if flipbook {
+		return its.saveFlipbook(frameGrids, outputDir, width, height)
+	}
+	return its.saveFramesAsSchems(frameGrids, outputDir, width, height)
+}
+
+// saveFramesAsSchems writes one .schem per frame into outputDir.
+func (its *ImageToSchem) saveFramesAsSchems(frameGrids [][][]Color, outputDir string, width, height int) error {
+	for i, grid := range frameGrids {
+		its.loadFrame(grid, width, height)
+		its.depth = 1
+		if err := its.GenerateSchem(); err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+		outPath := filepath.Join(outputDir, fmt.Sprintf("frame_%04d.schem", i))
+		if err := its.SaveSchem(outPath); err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// saveFlipbook generates each frame as its own flat layer, then stacks
+// them into a single schematic whose depth equals the frame count.
+func (its *ImageToSchem) saveFlipbook(frameGrids [][][]Color, outputDir string, width, height int) error {
+	depth := len(frameGrids)
+	blockData := make([][][]int, depth)
+
+	for z, grid := range frameGrids {
+		its.loadFrame(grid, width, height)
+		its.depth = 1
+		if err := its.GenerateSchem(); err != nil {
+			return fmt.Errorf("frame %d: %w", z, err)
+		}
+		blockData[z] = its.blockData[0]
+	}
+
+	its.width = width
+	its.height = height
+	its.depth = depth
+	its.blockData = blockData
+
+	return its.SaveSchem(filepath.Join(outputDir, "flipbook.schem"))
+}
+
+// loadFrame installs grid as the current source image, as if LoadImage
+// had just decoded it.
+func (its *ImageToSchem) loadFrame(grid [][]Color, width, height int) {
+	its.pixels = grid
+	its.originalHeight = len(grid)
+	its.originalWidth = 0
+	if its.originalHeight > 0 {
+		its.originalWidth = len(grid[0])
+	}
+	its.SetSize(width, height)
+}
+
+func frameToColorGrid(img image.Image) [][]Color {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	grid := make([][]Color, h)
+	for y := 0; y < h; y++ {
+		grid[y] = make([]Color, w)
+		for x := 0; x < w; x++ {
+			c := color.RGBAModel.Convert(img.At(x+bounds.Min.X, y+bounds.Min.Y)).(color.RGBA)
+			grid[y][x] = Color{c.R, c.G, c.B}
+		}
+	}
+	return grid
+}
+
+// quantizeFramesUnion runs median-cut once over every frame's pixels
+// combined, then replaces each pixel with its bucket's centroid - the
+// same palette reduction QuantizePalette does for a single image, just
+// applied across the whole animation so a color doesn't get mapped to a
+// different block from one frame to the next.
+func quantizeFramesUnion(frameGrids [][][]Color, maxColors int) {
+	var flat []Color
+	for _, grid := range frameGrids {
+		for _, row := range grid {
+			flat = append(flat, row...)
+		}
+	}
+
+	centroids, assignment := medianCut(flat, maxColors)
+
+	idx := 0
+	for _, grid := range frameGrids {
+		for y := range grid {
+			for x := range grid[y] {
+				grid[y][x] = centroids[assignment[idx]]
+				idx++
+			}
+		}
+	}
+}
+
+// decodeAnimationFrames decodes a multi-frame GIF or APNG file into one
+// composited image.Image per frame.
+func decodeAnimationFrames(path string) ([]image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if g, gifErr := gif.DecodeAll(bytes.NewReader(data)); gifErr == nil {
+		return compositeGIFFrames(g), nil
+	}
+
+	frames, apngErr := decodeAPNGFrames(data)
+	if apngErr != nil {
+		return nil, fmt.Errorf("unsupported animation format (expected GIF or APNG): %w", apngErr)
+	}
+	return frames, nil
+}
+
+// compositeGIFFrames draws each GIF frame onto a persistent canvas
+// (honoring DisposalBackground) so partial frames come out as full,
+// flattened images.
+func compositeGIFFrames(g *gif.GIF) []image.Image {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewRGBA(bounds)
+
+	frames := make([]image.Image, len(g.Image))
+	for i, frame := range g.Image {
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		out := image.NewRGBA(bounds)
+		draw.Draw(out, bounds, canvas, bounds.Min, draw.Src)
+		frames[i] = out
+
+		if i < len(g.Disposal) && g.Disposal[i] == gif.DisposalBackground {
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		}
+	}
+	return frames
+}
+
+// decodeAPNGFrames extracts each frame of an Animated PNG as a
+// standalone image by re-packaging its fcTL/IDAT/fdAT chunks into a
+// minimal single-frame PNG and decoding that with image/png. Frames are
+// assumed to cover the full canvas; dispose/blend op compositing isn't
+// implemented.
+func decodeAPNGFrames(data []byte) ([]image.Image, error) {
+	if len(data) < 8 || !bytes.Equal(data[:8], pngSignature) {
+		return nil, fmt.Errorf("not a PNG file")
+	}
+
+	type rawChunk struct {
+		typ  string
+		data []byte
+	}
+
+	var chunks []rawChunk
+	for pos := 8; pos+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		typ := string(data[pos+4 : pos+8])
+		if pos+8+length+4 > len(data) {
+			break
+		}
+		chunks = append(chunks, rawChunk{typ, data[pos+8 : pos+8+length]})
+		pos += 8 + length + 4
+		if typ == "IEND" {
+			break
+		}
+	}
+
+	var ihdr, plte, trns []byte
+	hasACTL := false
+
+	type frame struct {
+		fctl  []byte
+		parts [][]byte
+	}
+	var frames []frame
+	var current *frame
+
+	for _, c := range chunks {
+		switch c.typ {
+		case "IHDR":
+			ihdr = c.data
+		case "PLTE":
+			plte = c.data
+		case "tRNS":
+			trns = c.data
+		case "acTL":
+			hasACTL = true
+		case "fcTL":
+			if current != nil {
+				frames = append(frames, *current)
+			}
+			current = &frame{fctl: c.data}
+		case "IDAT":
+			if current != nil {
+				current.parts = append(current.parts, c.data)
+			}
+		case "fdAT":
+			if current != nil && len(c.data) > 4 {
+				current.parts = append(current.parts, c.data[4:]) // strip sequence number
+			}
+		}
+	}
+	if current != nil {
+		frames = append(frames, *current)
+	}
+
+	if !hasACTL || ihdr == nil || len(frames) == 0 {
+		return nil, fmt.Errorf("not an animated PNG")
+	}
+
+	images := make([]image.Image, 0, len(frames))
+	for _, f := range frames {
+		width := binary.BigEndian.Uint32(f.fctl[4:8])
+		height := binary.BigEndian.Uint32(f.fctl[8:12])
+
+		frameIHDR := append([]byte(nil), ihdr...)
+		binary.BigEndian.PutUint32(frameIHDR[0:4], width)
+		binary.BigEndian.PutUint32(frameIHDR[4:8], height)
+
+		var buf bytes.Buffer
+		buf.Write(pngSignature)
+		writePNGChunk(&buf, "IHDR", frameIHDR)
+		if plte != nil {
+			writePNGChunk(&buf, "PLTE", plte)
+		}
+		if trns != nil {
+			writePNGChunk(&buf, "tRNS", trns)
+		}
+		for _, part := range f.parts {
+			writePNGChunk(&buf, "IDAT", part)
+		}
+		writePNGChunk(&buf, "IEND", nil)
+
+		img, err := png.Decode(&buf)
+		if err != nil {
+			return nil, fmt.Errorf("decoding APNG frame: %w", err)
+		}
+		images = append(images, img)
+	}
+
+	return images, nil
+}
+
+func writePNGChunk(buf *bytes.Buffer, typ string, data []byte) {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	buf.Write(length[:])
+	buf.WriteString(typ)
+	buf.Write(data)
+
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	var crcBytes [4]byte
+	binary.BigEndian.PutUint32(crcBytes[:], crc.Sum32())
+	buf.Write(crcBytes[:])
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Shade is the map-art rendering of a column's height relative to its
+// northern neighbor: lower (Dark), unchanged (Normal), or raised (Light).
+type Shade int
+
+const (
+	ShadeDark Shade = iota
+	ShadeNormal
+	ShadeLight
+)
+
+// multiplier returns the shading factor Minecraft's map renderer applies
+// to a block's base color for this shade.
+func (s Shade) multiplier() float64 {
+	switch s {
+	case ShadeDark:
+		return 180.0 / 255.0
+	case ShadeLight:
+		return 255.0 / 255.0
+	default:
+		return 220.0 / 255.0
+	}
+}
+
+func shadeColor(base Color, s Shade) Color {
+	m := s.multiplier()
+	return Color{
+		clampChannel(float64(base.R) * m),
+		clampChannel(float64(base.G) * m),
+		clampChannel(float64(base.B) * m),
+	}
+}
+
+func shadeForStep(step int) Shade {
+	switch step {
+	case -1:
+		return ShadeDark
+	case 1:
+		return ShadeLight
+	default:
+		return ShadeNormal
+	}
+}
+
+// paletteIndex returns blockName's index in its.blockPalette, registering
+// it if this is the first time it's been seen.
+func (its *ImageToSchem) paletteIndex(blockName string) int {
+	for i, name := range its.blockPalette {
+		if name == blockName {
+			return i
+		}
+	}
+	its.blockPalette = append(its.blockPalette, blockName)
+	return len(its.blockPalette) - 1
+}
+
+// costForShade scans the loaded palette for the block whose color, once
+// rendered at shade s, lands closest to target. This has to be a direct
+// scan rather than FindClosestColor(target) followed by a shade
+// multiplier: FindClosestColor picks the block nearest target's raw,
+// unshaded color, which isn't necessarily the block that looks closest to
+// target once darkened or lightened.
+func (its *ImageToSchem) costForShade(target Color, s Shade) (BlockMapping, float64) {
+	var best BlockMapping
+	bestDist := math.MaxFloat64
+	found := false
+
+	for base, mapping := range its.colorToBlock {
+		dist := its.ColorDistance(target, shadeColor(base, s))
+		if dist < bestDist {
+			bestDist = dist
+			best = mapping
+			found = true
+		}
+	}
+
+	if !found {
+		return BlockMapping{"minecraft:white_concrete", 0}, bestDist
+	}
+	return best, bestDist
+}
+
+// generateMapArt implements MapArtMode: for each column it runs a DP over
+// the resampled rows that picks a height step (-1, 0 or +1 per row,
+// clamped to [its.minY, its.maxY]) minimizing total color distance, then
+// emits the resulting staircase plus a support block beneath every raised
+// block.
+func (its *ImageToSchem) generateMapArt() error {
+	if its.maxY < its.minY {
+		return fmt.Errorf("invalid map art height band: minY %d > maxY %d", its.minY, its.maxY)
+	}
+
+	grid := its.resampleGrid()
+	band := its.maxY - its.minY + 1
+
+	its.blockPalette = its.blockPalette[:0]
+	airIndex := its.paletteIndex("minecraft:air")
+	supportIndex := its.paletteIndex("minecraft:cobblestone")
+
+	its.depth = band
+	its.blockData = make([][][]int, band)
+	for z := 0; z < band; z++ {
+		its.blockData[z] = make([][]int, its.height)
+		for y := 0; y < its.height; y++ {
+			its.blockData[z][y] = make([]int, its.width)
+			for x := 0; x < its.width; x++ {
+				its.blockData[z][y][x] = airIndex
+			}
+		}
+	}
+
+	for x := 0; x < its.width; x++ {
+		its.resolveColumn(grid, x, band, supportIndex)
+	}
+
+	return nil
+}
+
+// resolveColumn runs the per-column height DP for column x and writes the
+// resulting staircase into its.blockData.
+func (its *ImageToSchem) resolveColumn(grid [][]Color, x, band, supportIndex int) {
+	type step struct {
+		mapping BlockMapping
+		cost    float64
+	}
+
+	// costs[y][shade] is the block costForShade picks for row y's target
+	// color at that shade, and its distance from the target.
+	costs := make([][3]step, its.height)
+	for y := 0; y < its.height; y++ {
+		target := grid[y][x]
+		for s := Shade(0); s < 3; s++ {
+			mapping, dist := its.costForShade(target, s)
+			costs[y][s] = step{mapping: mapping, cost: dist}
+		}
+	}
+
+	dp := make([][]float64, its.height)
+	back := make([][]int, its.height)
+	shadeUsed := make([][]Shade, its.height)
+	for y := 0; y < its.height; y++ {
+		dp[y] = make([]float64, band)
+		back[y] = make([]int, band)
+		shadeUsed[y] = make([]Shade, band)
+	}
+
+	for h := 0; h < band; h++ {
+		dp[0][h] = costs[0][ShadeNormal].cost
+		back[0][h] = -1
+		shadeUsed[0][h] = ShadeNormal
+	}
+
+	for y := 1; y < its.height; y++ {
+		for h := 0; h < band; h++ {
+			best := math.MaxFloat64
+			bestPrev := -1
+			bestShade := ShadeNormal
+
+			for _, step := range []int{-1, 0, 1} {
+				prev := h - step
+				if prev < 0 || prev >= band {
+					continue
+				}
+				s := shadeForStep(step)
+				cost := dp[y-1][prev] + costs[y][s].cost
+				if cost < best {
+					best = cost
+					bestPrev = prev
+					bestShade = s
+				}
+			}
+
+			dp[y][h] = best
+			back[y][h] = bestPrev
+			shadeUsed[y][h] = bestShade
+		}
+	}
+
+	bestH := 0
+	lastRow := its.height - 1
+	for h := 1; h < band; h++ {
+		if dp[lastRow][h] < dp[lastRow][bestH] {
+			bestH = h
+		}
+	}
+
+	h := bestH
+	for y := lastRow; y >= 0; y-- {
+		s := shadeUsed[y][h]
+		topName := costs[y][s].mapping.BlockName
+		if topName == "" {
+			topName = "minecraft:white_concrete"
+		}
+		topIndex := its.paletteIndex(topName)
+
+		its.blockData[h][y][x] = topIndex
+		if h > 0 {
+			its.blockData[h-1][y][x] = supportIndex
+		}
+
+		h = back[y][h]
+	}
+}
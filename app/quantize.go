@@ -0,0 +1,367 @@
+package main
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// kdNode is one node of the k-d tree built over the loaded block palette,
+// splitting alternately on the R, G and B channels.
+type kdNode struct {
+	color   Color
+	mapping BlockMapping
+	axis    int
+	left    *kdNode
+	right   *kdNode
+}
+
+func channelOf(c Color, axis int) float64 {
+	switch axis {
+	case 0:
+		return float64(c.R)
+	case 1:
+		return float64(c.G)
+	default:
+		return float64(c.B)
+	}
+}
+
+// buildKDTree recursively partitions entries, cycling the split channel
+// R, G, B by depth and splitting each node's subtree at the median so the
+// tree stays balanced.
+func buildKDTree(entries []Color, colorToBlock map[Color]BlockMapping, depth int) *kdNode {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	axis := depth % 3
+	sort.Slice(entries, func(i, j int) bool {
+		return channelOf(entries[i], axis) < channelOf(entries[j], axis)
+	})
+
+	mid := len(entries) / 2
+	node := &kdNode{
+		color:   entries[mid],
+		mapping: colorToBlock[entries[mid]],
+		axis:    axis,
+	}
+	node.left = buildKDTree(entries[:mid], colorToBlock, depth+1)
+	node.right = buildKDTree(entries[mid+1:], colorToBlock, depth+1)
+	return node
+}
+
+// kdQueueItem is a pending subtree in the best-bin-first search, ordered
+// by the lowest distance a color inside it could possibly achieve.
+type kdQueueItem struct {
+	node  *kdNode
+	bound float64
+}
+
+type kdPriorityQueue []kdQueueItem
+
+func (q kdPriorityQueue) Len() int            { return len(q) }
+func (q kdPriorityQueue) Less(i, j int) bool  { return q[i].bound < q[j].bound }
+func (q kdPriorityQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *kdPriorityQueue) Push(x interface{}) { *q = append(*q, x.(kdQueueItem)) }
+func (q *kdPriorityQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+// kdSearchBudget bounds how many nodes the best-bin-first search will pop
+// before returning its best candidate, so a pathological palette can't
+// turn a lookup back into a full linear scan.
+const kdSearchBudget = 64
+
+// kdDistance is the metric the tree is split and pruned on: the same
+// weighted-RGB redmean formula ColorDistance falls back to for
+// ColorSpaceRGB. The bound computed in nearest (a difference along one
+// raw R/G/B axis) is only a valid lower bound for *this* metric - it has
+// no defined relationship to nonlinear metrics like CIELAB's Delta-E, so
+// nearest always measures with kdDistance. Palettes queried in
+// ColorSpaceCIELAB fall back to a brute-force scan instead (see
+// FindClosestColor).
+func kdDistance(c1, c2 Color) float64 {
+	r1, g1, b1 := float64(c1.R), float64(c1.G), float64(c1.B)
+	r2, g2, b2 := float64(c2.R), float64(c2.G), float64(c2.B)
+	rMean := (r1 + r2) / 2
+
+	rDiff := r1 - r2
+	gDiff := g1 - g2
+	bDiff := b1 - b2
+
+	return math.Sqrt(
+		(2+rMean/256)*(rDiff*rDiff) +
+			4*(gDiff*gDiff) +
+			(2+(255-rMean)/256)*(bDiff*bDiff))
+}
+
+// kdAxisMinCoeff is the minimum weight kdDistance can ever apply to a
+// squared difference on a given axis (R=0, G=1, B=2). R and B are scaled
+// by 2+rMean/256 and 2+(255-rMean)/256, both of which range over [2, 3)
+// depending on rMean, so their true minimum is 2; G's weight is the fixed
+// constant 4. nearest uses this to turn a single-axis difference into a
+// valid lower bound on kdDistance itself.
+var kdAxisMinCoeff = [3]float64{2, 4, 2}
+
+// nearest runs a bounded best-bin-first search for the palette entry
+// closest to target under kdDistance, expanding the most promising
+// subtree first and giving up after kdSearchBudget nodes. It returns the
+// matched palette color itself alongside its block mapping, so callers
+// don't have to re-derive "which color matched this block name" later by
+// scanning the (unordered) palette map.
+func (root *kdNode) nearest(target Color) (Color, BlockMapping, bool) {
+	if root == nil {
+		return Color{}, BlockMapping{}, false
+	}
+
+	pq := &kdPriorityQueue{{node: root, bound: 0}}
+	heap.Init(pq)
+
+	found := false
+	var bestColor Color
+	var bestMapping BlockMapping
+	bestDist := maxFloat()
+
+	visited := 0
+	for pq.Len() > 0 && visited < kdSearchBudget {
+		item := heap.Pop(pq).(kdQueueItem)
+		node := item.node
+		if node == nil || item.bound > bestDist {
+			continue
+		}
+		visited++
+
+		d := kdDistance(target, node.color)
+		if d < bestDist {
+			bestDist = d
+			bestColor = node.color
+			bestMapping = node.mapping
+			found = true
+		}
+
+		diff := channelOf(target, node.axis) - channelOf(node.color, node.axis)
+		near, far := node.left, node.right
+		if diff > 0 {
+			near, far = node.right, node.left
+		}
+		if near != nil {
+			heap.Push(pq, kdQueueItem{node: near, bound: 0})
+		}
+		if far != nil {
+			// The far subtree can only contain a closer color if its
+			// splitting plane is nearer than the best match found so
+			// far. bestDist is in kdDistance's sqrt units, so the bound
+			// has to be converted to the same units rather than left as
+			// a squared raw-channel difference - scale by the minimum
+			// weight kdDistance can apply to this axis (see
+			// kdAxisMinCoeff) before taking the square root.
+			bound := math.Abs(diff) * math.Sqrt(kdAxisMinCoeff[node.axis])
+			heap.Push(pq, kdQueueItem{node: far, bound: bound})
+		}
+	}
+
+	return bestColor, bestMapping, found
+}
+
+func maxFloat() float64 {
+	var f float64 = 1
+	for i := 0; i < 1100; i++ {
+		f *= 2
+	}
+	return f
+}
+
+// bruteForceClosestColor linearly scans the loaded palette under
+// its.ColorDistance. Used instead of the k-d tree whenever the active
+// color space's distance isn't the metric the tree is pruned on. It
+// returns the matched palette color alongside its block mapping, the same
+// shape kdNode.nearest returns, so callers can treat the two uniformly.
+func (its *ImageToSchem) bruteForceClosestColor(target Color) (Color, BlockMapping, bool) {
+	var closest Color
+	minDistance := math.MaxFloat64
+	found := false
+
+	for colorKey := range its.colorToBlock {
+		distance := its.ColorDistance(target, colorKey)
+		if distance < minDistance {
+			minDistance = distance
+			closest = colorKey
+			found = true
+		}
+	}
+
+	if found {
+		return closest, its.colorToBlock[closest], true
+	}
+	return Color{}, BlockMapping{}, false
+}
+
+// ensurePaletteTree lazily (re)builds the k-d tree used by FindClosestColor
+// from the currently loaded palette.
+func (its *ImageToSchem) ensurePaletteTree() {
+	if its.paletteTree != nil {
+		return
+	}
+	entries := make([]Color, 0, len(its.colorToBlock))
+	for c := range its.colorToBlock {
+		entries = append(entries, c)
+	}
+	its.paletteTree = buildKDTree(entries, its.colorToBlock, 0)
+}
+
+// colorBucket holds the flat-pixel indices assigned to one median-cut
+// bucket, plus the running channel bounds used to pick the next split.
+type colorBucket struct {
+	indices    []int
+	minR, maxR uint8
+	minG, maxG uint8
+	minB, maxB uint8
+}
+
+func newColorBucket(indices []int, pixels []Color) colorBucket {
+	b := colorBucket{indices: indices}
+	b.minR, b.maxR = 255, 0
+	b.minG, b.maxG = 255, 0
+	b.minB, b.maxB = 255, 0
+	for _, idx := range indices {
+		c := pixels[idx]
+		if c.R < b.minR {
+			b.minR = c.R
+		}
+		if c.R > b.maxR {
+			b.maxR = c.R
+		}
+		if c.G < b.minG {
+			b.minG = c.G
+		}
+		if c.G > b.maxG {
+			b.maxG = c.G
+		}
+		if c.B < b.minB {
+			b.minB = c.B
+		}
+		if c.B > b.maxB {
+			b.maxB = c.B
+		}
+	}
+	return b
+}
+
+// widestAxis returns which channel (0=R, 1=G, 2=B) has the greatest range
+// in this bucket, along with that range.
+func (b colorBucket) widestAxis() (axis int, spread int) {
+	rRange := int(b.maxR) - int(b.minR)
+	gRange := int(b.maxG) - int(b.minG)
+	bRange := int(b.maxB) - int(b.minB)
+
+	axis, spread = 0, rRange
+	if gRange > spread {
+		axis, spread = 1, gRange
+	}
+	if bRange > spread {
+		axis, spread = 2, bRange
+	}
+	return axis, spread
+}
+
+// medianCut splits pixels into at most maxColors buckets by repeatedly
+// dividing the bucket with the greatest channel range at its median, then
+// returns one centroid color per bucket alongside each pixel's bucket
+// index.
+func medianCut(pixels []Color, maxColors int) (centroids []Color, assignment []int) {
+	if maxColors < 1 {
+		maxColors = 1
+	}
+
+	allIndices := make([]int, len(pixels))
+	for i := range pixels {
+		allIndices[i] = i
+	}
+	buckets := []colorBucket{newColorBucket(allIndices, pixels)}
+
+	for len(buckets) < maxColors {
+		splitIdx := -1
+		bestSpread := -1
+		for i, b := range buckets {
+			if len(b.indices) <= 1 {
+				continue
+			}
+			_, spread := b.widestAxis()
+			if spread > bestSpread {
+				bestSpread = spread
+				splitIdx = i
+			}
+		}
+		if splitIdx == -1 {
+			break
+		}
+
+		target := buckets[splitIdx]
+		axis, _ := target.widestAxis()
+		sort.Slice(target.indices, func(i, j int) bool {
+			return channelOf(pixels[target.indices[i]], axis) < channelOf(pixels[target.indices[j]], axis)
+		})
+
+		mid := len(target.indices) / 2
+		left := newColorBucket(target.indices[:mid], pixels)
+		right := newColorBucket(target.indices[mid:], pixels)
+
+		buckets[splitIdx] = left
+		buckets = append(buckets, right)
+	}
+
+	centroids = make([]Color, len(buckets))
+	assignment = make([]int, len(pixels))
+	for bi, b := range buckets {
+		var sumR, sumG, sumB int
+		for _, idx := range b.indices {
+			c := pixels[idx]
+			sumR += int(c.R)
+			sumG += int(c.G)
+			sumB += int(c.B)
+		}
+		n := len(b.indices)
+		if n == 0 {
+			continue
+		}
+		centroids[bi] = Color{uint8(sumR / n), uint8(sumG / n), uint8(sumB / n)}
+		for _, idx := range b.indices {
+			assignment[idx] = bi
+		}
+	}
+
+	return centroids, assignment
+}
+
+// QuantizePalette reduces the loaded image to at most maxColors distinct
+// colors via median-cut before block mapping runs. Every source pixel is
+// replaced by its bucket's centroid. GenerateSchem resamples after this
+// step, so its per-pixel lookups only stay within the centroid set when
+// no output pixel blends several source pixels (e.g. at 1:1 size);
+// averaging filters can produce colors outside it.
+func (its *ImageToSchem) QuantizePalette(maxColors int) error {
+	if its.originalWidth == 0 || its.originalHeight == 0 {
+		return errNoImageLoaded
+	}
+
+	flat := make([]Color, 0, its.originalWidth*its.originalHeight)
+	for y := 0; y < its.originalHeight; y++ {
+		flat = append(flat, its.pixels[y]...)
+	}
+
+	centroids, assignment := medianCut(flat, maxColors)
+
+	for y := 0; y < its.originalHeight; y++ {
+		rowOffset := y * its.originalWidth
+		for x := 0; x < its.originalWidth; x++ {
+			its.pixels[y][x] = centroids[assignment[rowOffset+x]]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// ResampleFilter selects how resampleGrid downscales the source image.
+type ResampleFilter int
+
+const (
+	FilterBox ResampleFilter = iota
+	FilterBilinear
+	FilterLanczos3
+	FilterCatmullRom
+)
+
+// --- EXIF orientation -------------------------------------------------
+
+// readExifOrientation scans a JPEG's markers for an EXIF Orientation tag
+// and returns its value (1-8), or 1 (no transform) if the file isn't a
+// JPEG, carries no EXIF APP1 segment, or has no Orientation tag.
+func readExifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			break
+		}
+		marker := data[pos+1]
+		pos += 2
+
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD9) {
+			continue // standalone marker, no length field
+		}
+		if marker == 0xDA {
+			break // start of scan data - no more metadata follows
+		}
+		if pos+2 > len(data) {
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos : pos+2]))
+		if length < 2 || pos+length > len(data) {
+			break
+		}
+		segment := data[pos+2 : pos+length]
+
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			if orientation := parseTIFFOrientation(segment[6:]); orientation != 0 {
+				return orientation
+			}
+		}
+
+		pos += length
+	}
+
+	return 1
+}
+
+// parseTIFFOrientation reads the Orientation tag (0x0112) out of a TIFF
+// header's IFD0, returning 0 if it's absent or malformed.
+func parseTIFFOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifdOffset := order.Uint32(tiff[4:8])
+	if int(ifdOffset)+2 > len(tiff) {
+		return 0
+	}
+
+	entryCount := order.Uint16(tiff[ifdOffset : ifdOffset+2])
+	base := int(ifdOffset) + 2
+
+	for i := 0; i < int(entryCount); i++ {
+		entryOffset := base + i*12
+		if entryOffset+12 > len(tiff) {
+			break
+		}
+		tag := order.Uint16(tiff[entryOffset : entryOffset+2])
+		if tag == 0x0112 {
+			return int(order.Uint16(tiff[entryOffset+8 : entryOffset+10]))
+		}
+	}
+
+	return 0
+}
+
+// applyExifOrientation rotates/flips pixels according to the standard
+// EXIF orientation values 1-8.
+func applyExifOrientation(pixels [][]Color, orientation int) [][]Color {
+	switch orientation {
+	case 2:
+		return flipHorizontal(pixels)
+	case 3:
+		return rotate180(pixels)
+	case 4:
+		return flipVertical(pixels)
+	case 5:
+		return transposeGrid(pixels)
+	case 6:
+		return rotate90CW(pixels)
+	case 7:
+		return rotate180(transposeGrid(pixels))
+	case 8:
+		return rotate270CW(pixels)
+	default:
+		return pixels
+	}
+}
+
+func flipHorizontal(px [][]Color) [][]Color {
+	out := make([][]Color, len(px))
+	for y, row := range px {
+		flipped := make([]Color, len(row))
+		for x, c := range row {
+			flipped[len(row)-1-x] = c
+		}
+		out[y] = flipped
+	}
+	return out
+}
+
+func flipVertical(px [][]Color) [][]Color {
+	out := make([][]Color, len(px))
+	for y, row := range px {
+		out[len(px)-1-y] = row
+	}
+	return out
+}
+
+func rotate180(px [][]Color) [][]Color {
+	return flipVertical(flipHorizontal(px))
+}
+
+func transposeGrid(px [][]Color) [][]Color {
+	rows := len(px)
+	if rows == 0 {
+		return px
+	}
+	cols := len(px[0])
+
+	out := make([][]Color, cols)
+	for x := 0; x < cols; x++ {
+		out[x] = make([]Color, rows)
+		for y := 0; y < rows; y++ {
+			out[x][y] = px[y][x]
+		}
+	}
+	return out
+}
+
+func rotate90CW(px [][]Color) [][]Color {
+	rows := len(px)
+	if rows == 0 {
+		return px
+	}
+	cols := len(px[0])
+
+	out := make([][]Color, cols)
+	for i := range out {
+		out[i] = make([]Color, rows)
+	}
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			out[x][rows-1-y] = px[y][x]
+		}
+	}
+	return out
+}
+
+func rotate270CW(px [][]Color) [][]Color {
+	rows := len(px)
+	if rows == 0 {
+		return px
+	}
+	cols := len(px[0])
+
+	out := make([][]Color, cols)
+	for i := range out {
+		out[i] = make([]Color, rows)
+	}
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			out[cols-1-x][y] = px[y][x]
+		}
+	}
+	return out
+}
+
+// --- Separable resampling filters --------------------------------------
+
+func bilinearKernel(x float64) float64 {
+	x = math.Abs(x)
+	if x < 1 {
+		return 1 - x
+	}
+	return 0
+}
+
+// catmullRomKernel is the Catmull-Rom cubic convolution kernel (a = -0.5).
+func catmullRomKernel(x float64) float64 {
+	x = math.Abs(x)
+	switch {
+	case x < 1:
+		return ((1.5*x-2.5)*x)*x + 1
+	case x < 2:
+		return (((-0.5*x+2.5)*x-4)*x + 2)
+	default:
+		return 0
+	}
+}
+
+// lanczos3Kernel is the windowed-sinc Lanczos kernel with a radius-3 lobe.
+func lanczos3Kernel(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	ax := math.Abs(x)
+	if ax >= 3 {
+		return 0
+	}
+	piX := math.Pi * x
+	return 3 * math.Sin(piX) * math.Sin(piX/3) / (piX * piX)
+}
+
+// resampleSeparable downscales the source image with a two-pass
+// (horizontal then vertical) convolution of kernel, widening the kernel's
+// support when downscaling so it still acts as a low-pass filter.
+func (its *ImageToSchem) resampleSeparable(kernel func(float64) float64, radius float64) [][]Color {
+	scaleX := float64(its.originalWidth) / float64(its.width)
+	scaleY := float64(its.originalHeight) / float64(its.height)
+
+	// Horizontal pass: resample each row from originalWidth to width.
+	horizontal := make([][]Color, its.originalHeight)
+	for y := 0; y < its.originalHeight; y++ {
+		horizontal[y] = make([]Color, its.width)
+		for x := 0; x < its.width; x++ {
+			srcX := (float64(x)+0.5)*scaleX - 0.5
+			horizontal[y][x] = convolve1D(its.pixels[y], srcX, scaleX, kernel, radius)
+		}
+	}
+
+	// Vertical pass: resample each column from originalHeight to height.
+	grid := make([][]Color, its.height)
+	for y := 0; y < its.height; y++ {
+		grid[y] = make([]Color, its.width)
+	}
+
+	column := make([]Color, its.originalHeight)
+	for x := 0; x < its.width; x++ {
+		for y := 0; y < its.originalHeight; y++ {
+			column[y] = horizontal[y][x]
+		}
+		for y := 0; y < its.height; y++ {
+			srcY := (float64(y)+0.5)*scaleY - 0.5
+			grid[y][x] = convolve1D(column, srcY, scaleY, kernel, radius)
+		}
+	}
+
+	return grid
+}
+
+// convolve1D weight-sums line around srcPos with kernel, widening the
+// kernel support by scale when downscaling (scale > 1) so high
+// frequencies get filtered out instead of aliasing.
+func convolve1D(line []Color, srcPos, scale float64, kernel func(float64) float64, radius float64) Color {
+	support := radius
+	kernelScale := 1.0
+	if scale > 1 {
+		support = radius * scale
+		kernelScale = scale
+	}
+
+	left := int(math.Floor(srcPos - support))
+	right := int(math.Ceil(srcPos + support))
+
+	var sumR, sumG, sumB, sumW float64
+	for i := left; i <= right; i++ {
+		if i < 0 || i >= len(line) {
+			continue
+		}
+		w := kernel((srcPos - float64(i)) / kernelScale)
+		if w == 0 {
+			continue
+		}
+		c := line[i]
+		sumR += float64(c.R) * w
+		sumG += float64(c.G) * w
+		sumB += float64(c.B) * w
+		sumW += w
+	}
+
+	if sumW == 0 {
+		return Color{255, 255, 255}
+	}
+	return Color{clampChannel(sumR / sumW), clampChannel(sumG / sumW), clampChannel(sumB / sumW)}
+}
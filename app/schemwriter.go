@@ -0,0 +1,232 @@
+package main
+
+import (
+	"compress/gzip"
+	"os"
+	"strings"
+
+	"github.com/MachineMC/NBT"
+)
+
+// SchematicWriter serializes a generated build to disk in a particular
+// schematic dialect.
+type SchematicWriter interface {
+	Write(its *ImageToSchem, outputPath string) error
+}
+
+// encodeVarint appends value to buf as an unsigned LEB128 varint, the
+// encoding Sponge schematics use for block-data indices so palettes
+// bigger than 128 entries don't truncate into a single byte.
+func encodeVarint(buf []byte, value int) []byte {
+	v := uint32(value)
+	for {
+		b := byte(v & 0x7F)
+		v >>= 7
+		if v != 0 {
+			buf = append(buf, b|0x80)
+		} else {
+			buf = append(buf, b)
+			break
+		}
+	}
+	return buf
+}
+
+// varintBlockData flattens its.blockData in Sponge's Y/Z/X (depth, row,
+// column) order into a varint-encoded byte stream.
+func varintBlockData(its *ImageToSchem) []byte {
+	out := make([]byte, 0, its.width*its.height*its.depth)
+	for z := 0; z < its.depth; z++ {
+		for y := 0; y < its.height; y++ {
+			for x := 0; x < its.width; x++ {
+				out = encodeVarint(out, its.blockData[z][y][x])
+			}
+		}
+	}
+	return out
+}
+
+func ensureSuffix(outputPath, suffix string) string {
+	if !strings.HasSuffix(strings.ToLower(outputPath), suffix) {
+		return outputPath + suffix
+	}
+	return outputPath
+}
+
+// writeGzippedNBT gzips outputPath and writes root through a big-endian
+// NBT encoder, matching what WorldEdit/FastAsyncWorldEdit expect.
+func writeGzippedNBT(outputPath string, root map[string]interface{}) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	defer gz.Close()
+
+	encoder := nbt.NewEncoderWithEncoding(gz, nbt.BigEndian)
+	return encoder.Encode(root)
+}
+
+// SpongeV2Writer writes the Sponge Schematic v2 layout: a flat top-level
+// compound with Palette/BlockData siblings.
+type SpongeV2Writer struct{}
+
+func (SpongeV2Writer) Write(its *ImageToSchem, outputPath string) error {
+	outputPath = ensureSuffix(outputPath, ".schem")
+
+	paletteMap := make(map[string]int32)
+	for i, blockName := range its.blockPalette {
+		paletteMap[blockName] = int32(i)
+	}
+
+	schematic := map[string]interface{}{
+		"Version":       int32(2),
+		"DataVersion":   int32(3100),
+		"Width":         int16(its.width),
+		"Height":        int16(its.depth),
+		"Length":        int16(its.height),
+		"Offset":        []int32{0, 0, 0},
+		"Palette":       paletteMap,
+		"BlockData":     varintBlockData(its),
+		"BlockEntities": []interface{}{},
+	}
+
+	return writeGzippedNBT(outputPath, schematic)
+}
+
+// SpongeV3Writer writes the Sponge Schematic v3 layout: everything moves
+// under a top-level "Schematic" compound, and Palette and the block data
+// move under a nested "Blocks" compound, where the block data is named
+// "Data".
+type SpongeV3Writer struct{}
+
+func (SpongeV3Writer) Write(its *ImageToSchem, outputPath string) error {
+	outputPath = ensureSuffix(outputPath, ".schem")
+
+	paletteMap := make(map[string]int32)
+	for i, blockName := range its.blockPalette {
+		paletteMap[blockName] = int32(i)
+	}
+
+	blocks := map[string]interface{}{
+		"Palette":       paletteMap,
+		"Data":          varintBlockData(its),
+		"BlockEntities": []interface{}{},
+	}
+
+	schematic := map[string]interface{}{
+		"Schematic": map[string]interface{}{
+			"Version":     int32(3),
+			"DataVersion": int32(3100),
+			"Width":       int16(its.width),
+			"Height":      int16(its.depth),
+			"Length":      int16(its.height),
+			"Offset":      []int32{0, 0, 0},
+			"Blocks":      blocks,
+		},
+	}
+
+	return writeGzippedNBT(outputPath, schematic)
+}
+
+// legacyBlockIDs maps a color-stripped block family (see legacyBaseName)
+// to its pre-1.13 numeric block ID, covering the wool/concrete/glass/clay
+// families this converter's default block selections draw from. Families
+// with no legacy numeric representation (anything introduced by the 1.13
+// flattening) fall back to stone in legacyBlockID.
+var legacyBlockIDs = map[string]int{
+	"stone":                 1,
+	"cobblestone":           4,
+	"wool":                  35,
+	"stained_glass":         95,
+	"stained_glass_pane":    160,
+	"terracotta":            159,
+	"stained_hardened_clay": 159,
+	"concrete":              251,
+	"concrete_powder":       252,
+	"air":                   0,
+}
+
+// legacyColorPrefixes are the dye-color name prefixes Minecraft uses for
+// wool/concrete/glass/clay variants.
+var legacyColorPrefixes = []string{
+	"white_", "orange_", "magenta_", "light_blue_", "yellow_", "lime_",
+	"pink_", "gray_", "light_gray_", "cyan_", "purple_", "blue_",
+	"brown_", "green_", "red_", "black_",
+}
+
+// legacyBaseName strips a "minecraft:" namespace and a leading dye-color
+// prefix (e.g. "minecraft:light_blue_concrete" -> "concrete") so every
+// colored variant of a block resolves to the same legacy family.
+func legacyBaseName(blockName string) string {
+	name := strings.TrimPrefix(blockName, "minecraft:")
+	for _, prefix := range legacyColorPrefixes {
+		if trimmed := strings.TrimPrefix(name, prefix); trimmed != name {
+			return trimmed
+		}
+	}
+	return name
+}
+
+// legacyBlockID resolves blockName to its pre-1.13 numeric block ID,
+// falling back to stone for any block family with no legacy ID.
+func legacyBlockID(blockName string) int {
+	if id, ok := legacyBlockIDs[legacyBaseName(blockName)]; ok {
+		return id
+	}
+	return legacyBlockIDs["stone"]
+}
+
+// MCEditWriter writes the legacy MCEdit/WorldEdit .schematic format:
+// numeric block IDs (see legacyBlockID) in a flat Blocks byte array, plus
+// a 4-bit Data nibble-array carrying each block's loaded block_data
+// metadata (e.g. wool/concrete dye color).
+type MCEditWriter struct{}
+
+func (MCEditWriter) Write(its *ImageToSchem, outputPath string) error {
+	outputPath = ensureSuffix(outputPath, ".schematic")
+
+	blockDataByName := make(map[string]int)
+	for _, mapping := range its.colorToBlock {
+		blockDataByName[mapping.BlockName] = mapping.BlockData
+	}
+
+	size := its.width * its.height * its.depth
+	blocks := make([]byte, size)
+	nibbles := make([]byte, (size+1)/2)
+
+	index := 0
+	for z := 0; z < its.depth; z++ {
+		for y := 0; y < its.height; y++ {
+			for x := 0; x < its.width; x++ {
+				blockName := its.blockPalette[its.blockData[z][y][x]]
+				blocks[index] = byte(legacyBlockID(blockName))
+				dataValue := byte(blockDataByName[blockName]) & 0x0F
+
+				if index%2 == 0 {
+					nibbles[index/2] |= dataValue
+				} else {
+					nibbles[index/2] |= dataValue << 4
+				}
+				index++
+			}
+		}
+	}
+
+	schematic := map[string]interface{}{
+		"Schematic": map[string]interface{}{
+			"Width":        int16(its.width),
+			"Height":       int16(its.depth),
+			"Length":       int16(its.height),
+			"Materials":    "Alpha",
+			"Blocks":       blocks,
+			"Data":         nibbles,
+			"Entities":     []interface{}{},
+			"TileEntities": []interface{}{},
+		},
+	}
+
+	return writeGzippedNBT(outputPath, schematic)
+}